@@ -0,0 +1,32 @@
+package jsonschema2go
+
+import "strings"
+
+// The draft identifiers surfaced on Result.Draft and accepted by
+// Job.DraftOverride. Taskcluster's own schemas are draft-04-ish and have
+// historically omitted $schema, hence the draft-04 default.
+const (
+	Draft04      = "draft-04"
+	Draft201909  = "2019-09"
+	Draft202012  = "2020-12"
+	draftDefault = Draft04
+)
+
+// detectDraft inspects a schema's $schema property to decide which draft
+// it declares, falling back to override (Job.DraftOverride), then
+// draftDefault, when $schema is absent. A $schema naming neither 2019-09
+// nor 2020-12 gives draftDefault. The result is recorded for
+// Result.Draft only: $defs/$anchor/$recursiveRef/unevaluatedProperties
+// are handled the same way whichever draft is detected.
+func detectDraft(schema *Schema, override string) string {
+	switch {
+	case schema.SchemaURI == "" && override != "":
+		return override
+	case strings.Contains(schema.SchemaURI, "2020-12"):
+		return Draft202012
+	case strings.Contains(schema.SchemaURI, "2019-09"):
+		return Draft201909
+	default:
+		return draftDefault
+	}
+}
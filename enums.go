@@ -0,0 +1,63 @@
+package jsonschema2go
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// UnknownEnumValueError is returned by a generated enum type's
+// UnmarshalJSON when the json value does not match any of the schema's
+// enum members.
+type UnknownEnumValueError struct {
+	Type  string
+	Value string
+}
+
+func (e *UnknownEnumValueError) Error() string {
+	return fmt.Sprintf("%q is not a known value for %s", e.Value, e.Type)
+}
+
+// isStringEnum reports whether the values of an enum are all strings
+// (as opposed to integers), which decides the generated type's
+// underlying type.
+func isStringEnum(enum []interface{}) bool {
+	for _, v := range enum {
+		if _, ok := v.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// writeEnum renders, via enum.tmpl, a named go type plus a const per
+// allowed value for a field constrained by "enum", along with
+// MarshalJSON/UnmarshalJSON/String() methods that translate between the
+// go constants and their json representation.
+func (g *generator) writeEnum(buf *bytes.Buffer, typeName string, enum []interface{}) error {
+	underlying := "string"
+	if !isStringEnum(enum) {
+		underlying = "int64"
+	}
+	members := make([]EnumMember, len(enum))
+	for i, v := range enum {
+		literal := fmt.Sprintf("%q", v)
+		if underlying != "string" {
+			literal = fmt.Sprintf("%v", v)
+		}
+		members[i] = EnumMember{
+			Name:         typeName + enumMemberName(v),
+			ConstLiteral: literal,
+			StringValue:  fmt.Sprint(v),
+		}
+	}
+	if err := g.renderer.Enum(buf, EnumData{Name: typeName, Underlying: underlying, Members: members}); err != nil {
+		return fmt.Errorf("jsonschema2go: enum.tmpl for %s: %v", typeName, err)
+	}
+	return nil
+}
+
+// enumMemberName derives the const identifier suffix for one enum value,
+// normalised the same way generated struct/field names are.
+func enumMemberName(v interface{}) string {
+	return normaliseIdentifier(fmt.Sprint(v))
+}
@@ -0,0 +1,366 @@
+package jsonschema2go
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// goType is a go struct generated from a json schema object, along with
+// enough of its originating schema to drive further code generation
+// (validators, enums, ...).
+type goType struct {
+	name    string // exported go type name
+	schema  *Schema
+	pointer string // json pointer to this schema within its document
+	fields  []goField
+}
+
+// goField is a single field of a goType.
+type goField struct {
+	name     string // exported go field name
+	jsonName string
+	goType   string // e.g. "string", "int64", "*Foo", "[]Bar"
+	schema   *Schema
+	required bool
+	pointer string // json pointer to this field's schema
+}
+
+// generator turns parsed schema documents into go source, honouring the
+// options set on Job.
+type generator struct {
+	job *Job
+
+	// patterns collects pattern-constrained strings, scoped by
+	// currentDocURL (see scopeKey), so a single package-level
+	// *regexp.Regexp var can be emitted per distinct pattern, instead of
+	// compiling it on every call, and each Job.Mappings output only gets
+	// the vars its own fields reference.
+	patterns map[string]map[string]string // scope -> pattern -> var name
+
+	// enumOrder/enumValues collect the named enum types discovered while
+	// walking fields (see registerEnum), keyed by scope then go type
+	// name, so each is emitted exactly once, into the output that
+	// referenced it.
+	enumOrder  map[string][]string
+	enumValues map[string]map[string][]interface{}
+
+	// the following are only populated by generateMulti (see mappings.go),
+	// while generating a Job.Mappings entry's output.
+	mappingsByURL  map[string]SchemaMapping
+	docsByURL      map[string]*schemaDocument
+	currentDocURL  string
+	currentPackage string
+	currentImports map[string]bool
+
+	// renderer renders struct/field/enum/header source; see render.go.
+	renderer Renderer
+}
+
+func newGenerator(j *Job) *generator {
+	return &generator{
+		job:        j,
+		patterns:   map[string]map[string]string{},
+		enumOrder:  map[string][]string{},
+		enumValues: map[string]map[string][]interface{}{},
+	}
+}
+
+func (g *generator) generate(docs []*schemaDocument) (*Result, error) {
+	renderer, err := g.job.renderer()
+	if err != nil {
+		return nil, err
+	}
+	g.renderer = renderer
+
+	if len(g.job.Mappings) > 0 {
+		return g.generateMulti(docs)
+	}
+	var buf bytes.Buffer
+	if err := renderer.Header(&buf, HeaderData{Package: g.job.Package}); err != nil {
+		return nil, fmt.Errorf("jsonschema2go: header.tmpl: %v", err)
+	}
+	for _, doc := range docs {
+		for _, t := range g.collectTypes(doc) {
+			if err := g.writeStruct(&buf, t); err != nil {
+				return nil, err
+			}
+			if g.job.GenerateValidators {
+				g.writeValidator(&buf, t)
+			}
+			g.writeUnevaluatedGuard(&buf, t)
+		}
+	}
+	for _, name := range g.enumOrder[""] {
+		if err := g.writeEnum(&buf, name, g.enumValues[""][name]); err != nil {
+			return nil, err
+		}
+	}
+	g.writeRegexpVars(&buf, "")
+	result := &Result{SourceCode: buf.Bytes()}
+	if len(docs) > 0 {
+		result.Draft = docs[0].draft
+	}
+	return result, nil
+}
+
+// registerEnum records a named enum type to be emitted once generation of
+// the current document's structs has finished, scoped to currentDocURL
+// so Job.Mappings emits it into the output that referenced it.
+func (g *generator) registerEnum(name string, values []interface{}) {
+	scope := g.currentDocURL
+	if g.enumValues[scope] == nil {
+		g.enumValues[scope] = map[string][]interface{}{}
+	}
+	if _, ok := g.enumValues[scope][name]; ok {
+		return
+	}
+	g.enumValues[scope][name] = values
+	g.enumOrder[scope] = append(g.enumOrder[scope], name)
+}
+
+// collectTypes walks a schema document and returns one goType per object
+// schema found, in a stable (depth-first, then alphabetical) order. Each
+// named definition (draft-04 "definitions" or draft 2019-09+ "$defs") is
+// also walked, so that a $ref to one still generates its type even if no
+// property reaches it directly.
+func (g *generator) collectTypes(doc *schemaDocument) []*goType {
+	var types []*goType
+	seen := map[string]bool{}
+	var walk func(s *Schema, fallbackName, pointer string)
+	walk = func(s *Schema, fallbackName, pointer string) {
+		if s == nil {
+			return
+		}
+		if isObjectSchema(s) {
+			name := exportedName(fallbackName, s.Title)
+			if seen[name] {
+				return
+			}
+			seen[name] = true
+			t := &goType{name: name, schema: s, pointer: pointer}
+			required := map[string]bool{}
+			for _, r := range s.Required {
+				required[r] = true
+			}
+			keys := make([]string, 0, len(s.Properties))
+			for k := range s.Properties {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				prop := s.Properties[k]
+				fieldPointer := pointer + "/properties/" + k
+				fieldName := exportedName(k, prop.Title)
+				fieldGoType := g.goTypeFor(prop, fieldName)
+				switch {
+				case prop.Ref != "":
+					if qualified, ok := g.qualifiedRef(prop.Ref); ok {
+						fieldGoType = qualified
+					} else if def, defName, ok := g.resolveLocalRef(doc, prop.Ref); ok {
+						fieldGoType = defName
+						defPointer := "/definitions/" + defName
+						if frag := strings.TrimPrefix(prop.Ref, "#"); strings.HasPrefix(frag, "/") {
+							defPointer = frag
+						}
+						walk(def, defName, defPointer)
+					}
+				case prop.RecursiveRef != "":
+					// draft 2019-09+: "$recursiveRef": "#" refers back to
+					// the nearest schema with a matching $recursiveAnchor,
+					// which in the common case is the root of the document.
+					fieldGoType = "*" + g.rootTypeName(doc)
+				}
+				if g.job.StrongEnums && len(prop.Enum) > 0 && !isObjectSchema(prop) {
+					fieldGoType = fieldName
+					g.registerEnum(fieldName, prop.Enum)
+				}
+				t.fields = append(t.fields, goField{
+					name:     fieldName,
+					jsonName: k,
+					goType:   fieldGoType,
+					schema:   prop,
+					required: required[k],
+					pointer:  fieldPointer,
+				})
+				walk(prop, fieldName, fieldPointer)
+			}
+			types = append(types, t)
+		}
+		if s.Items != nil {
+			walk(s.Items, fallbackName+"Item", pointer+"/items")
+		}
+	}
+	walk(doc.root, doc.root.Title, "")
+
+	defs := defsFor(doc.root)
+	defNames := make([]string, 0, len(defs))
+	for name := range defs {
+		defNames = append(defNames, name)
+	}
+	sort.Strings(defNames)
+	for _, name := range defNames {
+		prefix := "/definitions/"
+		if _, ok := doc.root.Defs[name]; ok {
+			prefix = "/$defs/"
+		}
+		walk(defs[name], name, prefix+name)
+	}
+	return types
+}
+
+// rootTypeName returns the go type name generated for doc's root schema.
+func (g *generator) rootTypeName(doc *schemaDocument) string {
+	return exportedName(doc.root.Title, doc.root.Title)
+}
+
+// resolveLocalRef resolves a same-document $ref against doc: either a
+// JSON pointer such as "#/definitions/Foo" / "#/$defs/Foo", or (draft
+// 2019-09+) a plain "#name" fragment matching some subschema's $anchor
+// or $dynamicAnchor.
+func (g *generator) resolveLocalRef(doc *schemaDocument, ref string) (*Schema, string, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, "", false
+	}
+	fragment := strings.TrimPrefix(ref, "#")
+	if fragment == "" {
+		return nil, "", false
+	}
+	if strings.HasPrefix(fragment, "/") {
+		pointer := strings.Trim(fragment, "/")
+		segments := strings.Split(pointer, "/")
+		key := segments[len(segments)-1]
+		def, ok := defsFor(doc.root)[key]
+		if !ok {
+			return nil, "", false
+		}
+		return def, exportedName(key, def.Title), true
+	}
+	def, ok := findByAnchor(doc.root, fragment)
+	if !ok {
+		return nil, "", false
+	}
+	return def, exportedName(fragment, def.Title), true
+}
+
+// findByAnchor walks schema's properties, items and definitions/$defs
+// (recursively) looking for a subschema whose $anchor or $dynamicAnchor
+// equals name.
+func findByAnchor(schema *Schema, name string) (*Schema, bool) {
+	if schema == nil {
+		return nil, false
+	}
+	if schema.Anchor == name || schema.DynamicAnchor == name {
+		return schema, true
+	}
+	for _, prop := range schema.Properties {
+		if found, ok := findByAnchor(prop, name); ok {
+			return found, true
+		}
+	}
+	if found, ok := findByAnchor(schema.Items, name); ok {
+		return found, true
+	}
+	for _, def := range defsFor(schema) {
+		if found, ok := findByAnchor(def, name); ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+// exportedName picks the go identifier for a schema: its Title if given,
+// otherwise a name derived from its location (property key, parent type).
+func exportedName(fallback, title string) string {
+	if title != "" {
+		return normaliseIdentifier(title)
+	}
+	return normaliseIdentifier(fallback)
+}
+
+// goTypeFor returns the go type used to represent s, recursing into
+// object/array schemas as needed.
+func (g *generator) goTypeFor(s *Schema, name string) string {
+	if isObjectSchema(s) {
+		return exportedName(name, s.Title)
+	}
+	t, _ := s.Type.(string)
+	switch t {
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		if s.Items != nil {
+			return "[]" + g.goTypeFor(s.Items, name+"Item")
+		}
+		return "[]interface{}"
+	default:
+		return "string"
+	}
+}
+
+func (g *generator) writeStruct(buf *bytes.Buffer, t *goType) error {
+	fields := make([]FieldData, len(t.fields))
+	for i, f := range t.fields {
+		fields[i] = FieldData{
+			Name:        f.name,
+			JSONName:    f.jsonName,
+			GoType:      f.goType,
+			Description: f.schema.Description,
+			Required:    f.required,
+			Pointer:     f.pointer,
+		}
+	}
+	if err := g.renderer.Struct(buf, StructData{
+		Name:        t.name,
+		Title:       t.schema.Title,
+		Description: t.schema.Description,
+		Required:    t.schema.Required,
+		Pointer:     t.pointer,
+		Fields:      fields,
+	}); err != nil {
+		return fmt.Errorf("jsonschema2go: struct.tmpl for %s: %v", t.name, err)
+	}
+	return nil
+}
+
+// patternVar returns the package-level *regexp.Regexp variable name used
+// to enforce pattern, registering it the first time it is seen within
+// currentDocURL's scope.
+func (g *generator) patternVar(pattern string) string {
+	scope := g.currentDocURL
+	vars := g.patterns[scope]
+	if vars == nil {
+		vars = map[string]string{}
+		g.patterns[scope] = vars
+	}
+	if name, ok := vars[pattern]; ok {
+		return name
+	}
+	name := fmt.Sprintf("pattern%d", len(vars))
+	vars[pattern] = name
+	return name
+}
+
+// writeRegexpVars emits the package-level regexp vars registered via
+// patternVar for scope, each compiled exactly once.
+func (g *generator) writeRegexpVars(buf *bytes.Buffer, scope string) {
+	vars := g.patterns[scope]
+	if len(vars) == 0 {
+		return
+	}
+	patterns := make([]string, 0, len(vars))
+	for p := range vars {
+		patterns = append(patterns, p)
+	}
+	sort.Strings(patterns)
+	fmt.Fprintf(buf, "var (\n")
+	for _, p := range patterns {
+		fmt.Fprintf(buf, "\t%s = regexp.MustCompile(%q)\n", vars[p], p)
+	}
+	fmt.Fprintf(buf, ")\n\n")
+}
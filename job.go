@@ -0,0 +1,97 @@
+// Package jsonschema2go generates go source code from json schema
+// documents.
+package jsonschema2go
+
+import "fmt"
+
+// Job describes a code-generation run: the schemas to read and the
+// options controlling how the generated go source is shaped.
+type Job struct {
+	// URLs is the list of root json schema documents to generate types
+	// for.
+	URLs []string
+	// Package is the go package name generated code is written under.
+	Package string
+	// ExportTypes is not read by the generator: generated type names are
+	// always exported (capitalised), whatever its value.
+	ExportTypes bool
+	// GenerateValidators, when true, emits a Validate() error method on
+	// each generated struct, enforcing the constraint keywords present in
+	// the source schema.
+	GenerateValidators bool
+	// StrongEnums, when true, emits string/integer json schema enums as
+	// named go types with a const per allowed value, MarshalJSON,
+	// UnmarshalJSON and String() methods, instead of representing them
+	// as plain strings.
+	StrongEnums bool
+	// Mappings, when non-empty, splits generated types across several
+	// packages/output files, one per schema URL, instead of a single
+	// Package/SourceCode result.
+	Mappings []SchemaMapping
+	// CacheDir, when set, caches downloaded schemas on disk (keyed by
+	// URL) alongside a jsonschema2go.lock file recording each schema's
+	// sha256, making generation reproducible across runs. See Offline
+	// and UpdateCache.
+	CacheDir string
+	// Offline, when true, resolves every schema from CacheDir instead of
+	// downloading it, failing if anything required is missing from the
+	// cache. Requires CacheDir.
+	Offline bool
+	// UpdateCache, when true, re-downloads schemas and refreshes the
+	// lockfile hashes in CacheDir instead of failing on a mismatch.
+	UpdateCache bool
+	// DraftOverride forces a specific json schema draft (Draft04,
+	// Draft201909 or Draft202012) to be used for schemas that do not
+	// declare a $schema property, instead of falling back to Draft04.
+	DraftOverride string
+	// TemplateDir, when set, is scanned for struct.tmpl/field.tmpl/
+	// enum.tmpl/header.tmpl files overriding the built-in templates used
+	// by the default Renderer. Ignored if Renderer is set.
+	TemplateDir string
+	// Renderer, when set, replaces the default text/template based
+	// Renderer used to emit struct/field/enum source.
+	Renderer Renderer
+}
+
+// renderer returns j.Renderer if set, otherwise the default
+// text/template based Renderer, with j.TemplateDir applied.
+func (j *Job) renderer() (Renderer, error) {
+	if j.Renderer != nil {
+		return j.Renderer, nil
+	}
+	return newRenderer(j.TemplateDir)
+}
+
+// Result is returned by Job.Execute.
+type Result struct {
+	// SourceCode holds the generated, not yet gofmt'd, go source, when
+	// Job.Mappings was not used to split generation across several
+	// packages.
+	SourceCode []byte
+	// Outputs holds one entry per Job.Mappings entry, when Job.Mappings
+	// was used to split generation across several packages.
+	Outputs []Output
+	// Draft is the json schema draft detected (or forced via
+	// Job.DraftOverride) for the first schema processed by this job,
+	// surfaced for diagnostics.
+	Draft string
+}
+
+// Execute runs the code generation job described by j and returns the
+// resulting source code.
+func (j *Job) Execute() (*Result, error) {
+	loader, err := newSchemaLoader(j)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]*schemaDocument, 0, len(j.URLs))
+	for _, u := range j.URLs {
+		doc, err := loader.Load(u)
+		if err != nil {
+			return nil, fmt.Errorf("jsonschema2go: could not load schema %q: %v", u, err)
+		}
+		docs = append(docs, doc)
+	}
+	g := newGenerator(j)
+	return g.generate(docs)
+}
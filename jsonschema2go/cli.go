@@ -10,6 +10,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
 
 	"golang.org/x/tools/imports"
 
@@ -29,6 +30,21 @@ func readStringStrip(reader *bufio.Reader, delimeter byte) (string, error) {
 	return token, nil
 }
 
+// parseMappings splits a list of "URI=VALUE" strings, as passed to
+// --schema-package/--schema-import/--schema-output, into a map keyed by
+// URI.
+func parseMappings(flag string, raw []string) (map[string]string, error) {
+	mappings := map[string]string{}
+	for _, entry := range raw {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("%v value %q is not of the form URI=VALUE", flag, entry)
+		}
+		mappings[parts[0]] = parts[1]
+	}
+	return mappings, nil
+}
+
 func parseStandardIn() ([]string, error) {
 	results := []string{}
 	reader := bufio.NewReader(os.Stdin)
@@ -65,7 +81,7 @@ Examples:
   jsonschema2go --in "https://.../url1 file:///Users/pmoore/myschema.yml" --build '!windows' -- monkey
 
 Usage:
-  jsonschema2go [--in INPUT-URLS] [--out OUTPUT-FILE] [--build BUILD-DIRECTIVES] [--] GO-PACKAGE-NAME
+  jsonschema2go [--in INPUT-URLS] [--out OUTPUT-FILE] [--build BUILD-DIRECTIVES] [--validate] [--enums] [--schema-package=<mapping>...] [--schema-import=<mapping>...] [--schema-output=<mapping>...] [--cache DIR] [--offline] [--update] [--draft DRAFT] [--templates DIR] [--] GO-PACKAGE-NAME
   jsonschema2go -h|--help
   jsonschema2go --version
 
@@ -79,6 +95,55 @@ Options:
 --build BUILD-DIRECTIVES   If build directives are specified, the generated
                            code will begin with the line:
                            '// +build <BUILD-DIRECTIVES>'
+--validate                 If set, each generated struct will also get a
+                           Validate() error method that enforces the
+                           constraints declared in the source json schema
+                           (minimum/maximum, pattern, enum, required, etc).
+                           Validation errors are returned as
+                           jsonschema2go.ValidationError values.
+--enums                    If set, string/integer enum schemas are generated
+                           as named Go types with a const per allowed value,
+                           rather than plain strings, along with
+                           MarshalJSON/UnmarshalJSON/String() methods.
+                           Unrecognised values fail to unmarshal with an
+                           UnknownEnumValueError.
+--schema-package=<mapping> A URI=PACKAGE mapping, assigning the types
+                           generated from the schema at URI to the go
+                           package PACKAGE. May be repeated to generate
+                           several packages from a single invocation. URI
+                           must also be passed to --in or standard in.
+--schema-import=<mapping>  A URI=IMPORTPATH mapping, giving the import
+                           path other --schema-package packages should
+                           use to reach the package associated with URI,
+                           when one mapping's schema is $ref'd by
+                           another's. May be repeated.
+--schema-output=<mapping>  A URI=FILE mapping, assigning the output file
+                           that the package associated with URI (via
+                           --schema-package) is written to. May be
+                           repeated.
+--cache DIR                Directory to cache downloaded json schemas in,
+                           keyed by URL, along with a jsonschema2go.lock
+                           file recording each URL's resolved $id and a
+                           sha256 of its bytes. Makes code generation
+                           reproducible across runs.
+--offline                  Read every input schema (--in or standard in)
+                           from --cache DIR rather than downloading it,
+                           failing if one is missing from the cache.
+                           Requires --cache. $ref'd schemas that are not
+                           themselves inputs are not loaded.
+--update                   Re-download schemas and refresh the lockfile
+                           hashes in --cache DIR instead of failing on a
+                           mismatch.
+--draft DRAFT              Force a specific json schema draft (e.g.
+                           "draft-04", "2019-09", "2020-12") for schemas
+                           that do not declare a $schema. If not given,
+                           the draft is detected per schema from its
+                           $schema property, defaulting to draft-04.
+--templates DIR            A directory of .tmpl files (struct.tmpl,
+                           field.tmpl, enum.tmpl, header.tmpl) overriding
+                           the built-in templates used to render generated
+                           code, for adding custom struct tags or
+                           interfaces without forking jsonschema2go.
 -h --help                  Display this help text.
 --version                  Display the version information of jsonschema2go.
 `
@@ -99,20 +164,68 @@ func main() {
 			log.Fatalf("jsonschema2go: Could not read input URLs from standard in: '%#v'", err)
 		}
 	}
+	schemaPackages, err := parseMappings("--schema-package", arguments["--schema-package"].([]string))
+	if err != nil {
+		log.Fatalf("jsonschema2go: %v", err)
+	}
+	schemaImports, err := parseMappings("--schema-import", arguments["--schema-import"].([]string))
+	if err != nil {
+		log.Fatalf("jsonschema2go: %v", err)
+	}
+	schemaOutputs, err := parseMappings("--schema-output", arguments["--schema-output"].([]string))
+	if err != nil {
+		log.Fatalf("jsonschema2go: %v", err)
+	}
+	mappings := make([]jsonschema2go.SchemaMapping, 0, len(schemaPackages))
+	for url, pkg := range schemaPackages {
+		mappings = append(mappings, jsonschema2go.SchemaMapping{
+			URL:        url,
+			Package:    pkg,
+			ImportPath: schemaImports[url],
+			Output:     schemaOutputs[url],
+		})
+	}
 	job := &jsonschema2go.Job{
-		Package:     arguments["GO-PACKAGE-NAME"].(string),
-		ExportTypes: true,
-		URLs:        urls,
+		Package:            arguments["GO-PACKAGE-NAME"].(string),
+		ExportTypes:        true,
+		URLs:               urls,
+		GenerateValidators: arguments["--validate"].(bool),
+		StrongEnums:        arguments["--enums"].(bool),
+		Mappings:           mappings,
+		Offline:            arguments["--offline"].(bool),
+		UpdateCache:        arguments["--update"].(bool),
+	}
+	if cacheDir := arguments["--cache"]; cacheDir != nil {
+		job.CacheDir = cacheDir.(string)
+	}
+	if draft := arguments["--draft"]; draft != nil {
+		job.DraftOverride = draft.(string)
+	}
+	if templateDir := arguments["--templates"]; templateDir != nil {
+		job.TemplateDir = templateDir.(string)
 	}
 	result, err := job.Execute()
 	if err != nil {
 		log.Fatalf("jsonschema2go: Could not generate source code: '%#v'", err)
 	}
+	if result.Draft != "" {
+		log.Printf("jsonschema2go: detected json schema draft: %v", result.Draft)
+	}
 	if directives := arguments["BUILD-DIRECTIVES"]; directives != nil {
 		result.SourceCode = append([]byte("// +build "+directives.(string)+"\n"), result.SourceCode...)
 	}
+	if len(mappings) > 0 {
+		files := make([]outputFile, len(result.Outputs))
+		for i, o := range result.Outputs {
+			files[i] = outputFile{path: o.Path, code: o.SourceCode}
+		}
+		if err := formatSourceAndSave(files); err != nil {
+			log.Fatalf("jsonschema2go: Could not write generated packages: '%#v'", err)
+		}
+		return
+	}
 	if out := arguments["OUTPUT-FILE"]; out != nil {
-		err = formatSourceAndSave(out.(string), result.SourceCode)
+		err = formatSourceAndSave([]outputFile{{path: out.(string), code: result.SourceCode}})
 		if err != nil {
 			log.Fatalf("jsonschema2go: Could not create file '%v'", out)
 		}
@@ -121,7 +234,36 @@ func main() {
 	}
 }
 
-func formatSourceAndSave(sourceFile string, sourceCode []byte) error {
+// outputFile pairs a file to write generated source code to with the
+// source code itself.
+type outputFile struct {
+	path string
+	code []byte
+}
+
+// formatSourceAndSave runs goimports and gofmt over each of the given
+// files and writes the result, in parallel, since with --schema-package /
+// --schema-output a single invocation may need to emit many files.
+func formatSourceAndSave(files []outputFile) error {
+	errs := make([]error, len(files))
+	var wg sync.WaitGroup
+	for i, f := range files {
+		wg.Add(1)
+		go func(i int, f outputFile) {
+			defer wg.Done()
+			errs[i] = formatAndSaveOne(f.path, f.code)
+		}(i, f)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatAndSaveOne(sourceFile string, sourceCode []byte) error {
 	// first run goimports to clean up unused imports
 	fixedImports, err := imports.Process(sourceFile, sourceCode, nil)
 	var formattedContent []byte
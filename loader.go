@@ -0,0 +1,176 @@
+package jsonschema2go
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// lockFileName is the file written/read under Job.CacheDir recording the
+// sha256 of every schema that has been fetched.
+const lockFileName = "jsonschema2go.lock"
+
+// lockEntry records, for one schema URL, the resolved canonical $id (when
+// known) and a sha256 of the bytes that were fetched.
+type lockEntry struct {
+	URL    string `json:"url"`
+	ID     string `json:"id,omitempty"`
+	SHA256 string `json:"sha256"`
+}
+
+// lockFile is the on-disk representation of jsonschema2go.lock.
+type lockFile struct {
+	Entries map[string]lockEntry `json:"entries"`
+}
+
+// schemaLoader fetches and parses json schema documents referenced by a
+// Job, either directly from their URL or, when Job.CacheDir is set, from
+// a local on-disk cache verified against a jsonschema2go.lock file.
+type schemaLoader struct {
+	job  *Job
+	lock lockFile
+}
+
+func newSchemaLoader(j *Job) (*schemaLoader, error) {
+	l := &schemaLoader{job: j, lock: lockFile{Entries: map[string]lockEntry{}}}
+	if j.Offline && j.CacheDir == "" {
+		return nil, fmt.Errorf("jsonschema2go: Offline requires CacheDir to also be set")
+	}
+	if j.CacheDir == "" {
+		return l, nil
+	}
+	if err := os.MkdirAll(j.CacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create cache dir %q: %v", j.CacheDir, err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(j.CacheDir, lockFileName))
+	switch {
+	case os.IsNotExist(err):
+		// no lockfile yet; fine for a first/--update run
+	case err != nil:
+		return nil, fmt.Errorf("could not read %v: %v", lockFileName, err)
+	default:
+		if err := json.Unmarshal(data, &l.lock); err != nil {
+			return nil, fmt.Errorf("could not parse %v: %v", lockFileName, err)
+		}
+	}
+	if l.lock.Entries == nil {
+		l.lock.Entries = map[string]lockEntry{}
+	}
+	return l, nil
+}
+
+// Load fetches and parses the schema document at rawURL.
+func (l *schemaLoader) Load(rawURL string) (*schemaDocument, error) {
+	data, err := l.fetch(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	schema := &Schema{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return nil, fmt.Errorf("could not parse json schema: %v", err)
+	}
+	schema.resolvedURL = rawURL
+	doc := &schemaDocument{url: rawURL, root: schema, draft: detectDraft(schema, l.job.DraftOverride)}
+	if l.job.CacheDir != "" {
+		l.recordEntry(rawURL, schema.ID, data)
+		if err := l.writeLock(); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// fetch returns the raw bytes of the schema at rawURL, honouring
+// Job.CacheDir/Offline/UpdateCache.
+func (l *schemaLoader) fetch(rawURL string) ([]byte, error) {
+	if l.job.CacheDir == "" {
+		return download(rawURL)
+	}
+	path := l.cachePath(rawURL)
+	cached, readErr := ioutil.ReadFile(path)
+	haveCached := readErr == nil
+
+	switch {
+	case l.job.Offline && !haveCached:
+		return nil, fmt.Errorf("--offline: %q is not present in cache %v", rawURL, l.job.CacheDir)
+	case l.job.Offline:
+		if err := l.verify(rawURL, cached); err != nil {
+			return nil, err
+		}
+		return cached, nil
+	case haveCached && !l.job.UpdateCache:
+		if err := l.verify(rawURL, cached); err != nil {
+			return nil, fmt.Errorf("%v (pass --update to refresh the cache)", err)
+		}
+		return cached, nil
+	}
+
+	data, err := download(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("could not write cache entry for %q: %v", rawURL, err)
+	}
+	return data, nil
+}
+
+// verify checks data against the sha256 recorded in the lockfile for
+// rawURL, if any. A schema that has never been locked (e.g. the very
+// first run, before writeLock has run) is considered valid.
+func (l *schemaLoader) verify(rawURL string, data []byte) error {
+	entry, ok := l.lock.Entries[rawURL]
+	if !ok {
+		return nil
+	}
+	if got := sha256Hex(data); entry.SHA256 != got {
+		return fmt.Errorf("cached schema %q has changed (jsonschema2go.lock has sha256 %v, got %v)", rawURL, entry.SHA256, got)
+	}
+	return nil
+}
+
+func (l *schemaLoader) recordEntry(rawURL, id string, data []byte) {
+	l.lock.Entries[rawURL] = lockEntry{URL: rawURL, ID: id, SHA256: sha256Hex(data)}
+}
+
+func (l *schemaLoader) writeLock() error {
+	data, err := json.MarshalIndent(l.lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(l.job.CacheDir, lockFileName), data, 0644)
+}
+
+func (l *schemaLoader) cachePath(rawURL string) string {
+	return filepath.Join(l.job.CacheDir, sha256Hex([]byte(rawURL))+".json")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func download(rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "file" || u.Scheme == "" {
+		return ioutil.ReadFile(u.Path)
+	}
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %v: unexpected status %v", rawURL, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,169 @@
+package jsonschema2go
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SchemaMapping assigns the types generated from the schema at URL to a
+// specific go package and output file, so a single Job can produce
+// several packages in one invocation.
+type SchemaMapping struct {
+	URL     string
+	Package string
+	// ImportPath is the string used in a generated import (...) block to
+	// reach Package from another mapping's output. It is only required
+	// for mappings that other mappings' schemas $ref.
+	ImportPath string
+	Output     string
+}
+
+// Output is one generated source file, produced per SchemaMapping entry
+// when Job.Mappings is used.
+type Output struct {
+	Package    string
+	Path       string
+	SourceCode []byte
+}
+
+// generateMulti groups the types collected from docs by the schema they
+// originated from, emitting one *bytes.Buffer (and Output) per
+// Job.Mappings entry. A field whose schema $ref's another mapping's URL
+// is rendered as a qualified reference (pkg.Type) and that mapping's
+// ImportPath is added to the output's import block.
+func (g *generator) generateMulti(docs []*schemaDocument) (*Result, error) {
+	byURL := map[string]SchemaMapping{}
+	for _, m := range g.job.Mappings {
+		byURL[m.URL] = m
+	}
+	g.mappingsByURL = byURL
+	docsByURL := make(map[string]*schemaDocument, len(docs))
+	for _, doc := range docs {
+		docsByURL[doc.url] = doc
+	}
+	g.docsByURL = docsByURL
+
+	type perPackage struct {
+		mapping SchemaMapping
+		buf     bytes.Buffer
+		imports map[string]bool // import paths imported by this output
+	}
+	packages := make(map[string]*perPackage, len(docs))
+	order := make([]string, 0, len(docs))
+
+	for _, doc := range docs {
+		mapping, ok := byURL[doc.url]
+		if !ok {
+			return nil, fmt.Errorf("jsonschema2go: no --schema-package mapping given for schema %q", doc.url)
+		}
+		pp, ok := packages[mapping.URL]
+		if !ok {
+			pp = &perPackage{mapping: mapping, imports: map[string]bool{}}
+			packages[mapping.URL] = pp
+			order = append(order, mapping.URL)
+		}
+		g.currentDocURL = doc.url
+		g.currentPackage = mapping.Package
+		g.currentImports = pp.imports
+		for _, t := range g.collectTypes(doc) {
+			if err := g.writeStruct(&pp.buf, t); err != nil {
+				return nil, err
+			}
+			if g.job.GenerateValidators {
+				g.writeValidator(&pp.buf, t)
+			}
+			g.writeUnevaluatedGuard(&pp.buf, t)
+		}
+	}
+
+	// enums and pattern regexps are scoped per schema URL (see
+	// registerEnum/patternVar), so each output only gets the ones its own
+	// fields actually reference.
+	for _, url := range order {
+		pp := packages[url]
+		for _, name := range g.enumOrder[url] {
+			if err := g.writeEnum(&pp.buf, name, g.enumValues[url][name]); err != nil {
+				return nil, err
+			}
+		}
+		g.writeRegexpVars(&pp.buf, url)
+	}
+
+	outputs := make([]Output, 0, len(order))
+	for _, url := range order {
+		pp := packages[url]
+		var header bytes.Buffer
+		if err := g.renderer.Header(&header, HeaderData{Package: pp.mapping.Package}); err != nil {
+			return nil, fmt.Errorf("jsonschema2go: header.tmpl: %v", err)
+		}
+		if len(pp.imports) > 0 {
+			paths := make([]string, 0, len(pp.imports))
+			for p := range pp.imports {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			fmt.Fprintf(&header, "import (\n")
+			for _, p := range paths {
+				fmt.Fprintf(&header, "\t%q\n", p)
+			}
+			fmt.Fprintf(&header, ")\n\n")
+		}
+		source := append(header.Bytes(), pp.buf.Bytes()...)
+		outputs = append(outputs, Output{
+			Package:    pp.mapping.Package,
+			Path:       pp.mapping.Output,
+			SourceCode: source,
+		})
+	}
+	result := &Result{Outputs: outputs}
+	if len(docs) > 0 {
+		result.Draft = docs[0].draft
+	}
+	return result, nil
+}
+
+// qualifiedRef resolves a $ref that points at another schema mapping's
+// URL to a package-qualified go type name, recording that mapping's
+// ImportPath on the current output as a side effect. A $ref with no
+// fragment (a whole-document reference) resolves to the referenced
+// schema's own root type. ok is false when ref does not point at a
+// mapped schema, or that mapping has no ImportPath to import it by.
+func (g *generator) qualifiedRef(ref string) (goType string, ok bool) {
+	parts := strings.SplitN(ref, "#", 2)
+	url := parts[0]
+	if url == "" || url == g.currentDocURL {
+		return "", false
+	}
+	mapping, found := g.mappingsByURL[url]
+	if !found || mapping.ImportPath == "" {
+		return "", false
+	}
+	pointer := ""
+	if len(parts) == 2 {
+		pointer = strings.Trim(parts[1], "/")
+	}
+	var name string
+	if pointer == "" {
+		doc, ok := g.docsByURL[url]
+		if !ok {
+			return "", false
+		}
+		name = g.rootTypeName(doc)
+	} else {
+		segments := strings.Split(pointer, "/")
+		key := segments[len(segments)-1]
+		title := ""
+		if doc, ok := g.docsByURL[url]; ok {
+			if def, ok := defsFor(doc.root)[key]; ok {
+				title = def.Title
+			}
+		}
+		name = exportedName(key, title)
+	}
+	if g.currentImports != nil {
+		g.currentImports[mapping.ImportPath] = true
+	}
+	return mapping.Package + "." + name, true
+}
@@ -0,0 +1,33 @@
+package jsonschema2go
+
+import (
+	"strings"
+	"unicode"
+)
+
+// normaliseIdentifier converts an arbitrary json schema title, property
+// name or enum value into an exported go identifier, e.g.
+// "task group id" -> "TaskGroupId", "pending-retry" -> "PendingRetry".
+// Enum value identifiers are derived the same way as struct/type names,
+// so the two stay visually consistent in generated code.
+func normaliseIdentifier(s string) string {
+	var b strings.Builder
+	upperNext := true
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if upperNext {
+				b.WriteRune(unicode.ToUpper(r))
+				upperNext = false
+			} else {
+				b.WriteRune(r)
+			}
+		default:
+			upperNext = true
+		}
+	}
+	if b.Len() == 0 {
+		return "Value"
+	}
+	return b.String()
+}
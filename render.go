@@ -0,0 +1,159 @@
+package jsonschema2go
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"text/template"
+)
+
+// Renderer renders the pieces of a generated go file: the package
+// header, struct definitions, individual fields, and enum types.
+// Job.Renderer lets a caller replace it entirely; Job.TemplateDir lets a
+// caller override individual .tmpl files of the default, text/template
+// based Renderer without forking jsonschema2go.
+type Renderer interface {
+	Header(w io.Writer, data HeaderData) error
+	Struct(w io.Writer, data StructData) error
+	Field(w io.Writer, data FieldData) error
+	Enum(w io.Writer, data EnumData) error
+}
+
+// HeaderData is the template data model for header.tmpl.
+type HeaderData struct {
+	Package string
+}
+
+// FieldData is the template data model for field.tmpl, exposing enough
+// of the originating schema for a custom template to add struct tags
+// (validate:"...", bson:"...") or otherwise change how a field is
+// declared.
+type FieldData struct {
+	Name        string // go field name
+	JSONName    string
+	GoType      string
+	Description string
+	Required    bool
+	Pointer     string // json pointer to this field's schema
+}
+
+// StructData is the template data model for struct.tmpl.
+type StructData struct {
+	Name        string
+	Title       string
+	Description string
+	Required    []string
+	Pointer     string // json pointer to this schema
+	Fields      []FieldData
+}
+
+// EnumMember is one const generated for an EnumData.
+type EnumMember struct {
+	Name        string // go const identifier
+	ConstLiteral string // go literal assigned to the const, already quoted if needed
+	StringValue string // the enum value's string form, as returned by String()
+}
+
+// EnumData is the template data model for enum.tmpl.
+type EnumData struct {
+	Name       string
+	Underlying string // "string" or "int64"
+	Members    []EnumMember
+}
+
+// builtinTemplates defines the four overridable templates: header.tmpl,
+// struct.tmpl, field.tmpl and enum.tmpl. A file of the same name dropped
+// into Job.TemplateDir replaces the matching definition below.
+const builtinTemplates = `
+{{define "header.tmpl"}}package {{.Package}}
+
+{{end}}
+{{define "field.tmpl"}}	{{.Name}} {{.GoType}} ` + "`json:\"{{.JSONName}}\"`" + `
+{{end}}
+{{define "struct.tmpl"}}{{if .Description}}// {{.Name}} {{.Description}}
+{{end}}type {{.Name}} struct {
+{{range .Fields}}{{template "field.tmpl" .}}{{end}}}
+
+{{end}}
+{{define "enum.tmpl"}}type {{.Name}} {{.Underlying}}
+
+const (
+{{range .Members}}	{{.Name}} {{$.Name}} = {{.ConstLiteral}}
+{{end}})
+
+func (v {{.Name}}) String() string {
+	switch v {
+{{range .Members}}	case {{.Name}}:
+		return {{printf "%q" .StringValue}}
+{{end}}	}
+	return ""
+}
+
+func (v {{.Name}}) MarshalJSON() ([]byte, error) {
+	return json.Marshal({{.Underlying}}(v))
+}
+
+func (v *{{.Name}}) UnmarshalJSON(data []byte) error {
+	var raw {{.Underlying}}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch {{.Name}}(raw) {
+{{range .Members}}	case {{.Name}}:
+		*v = {{$.Name}}(raw)
+		return nil
+{{end}}	}
+	return &jsonschema2go.UnknownEnumValueError{Type: {{printf "%q" .Name}}, Value: fmt.Sprint(raw)}
+}
+
+{{end}}
+`
+
+// templateRenderer is the default Renderer, backed by text/template.
+type templateRenderer struct {
+	tmpl *template.Template
+}
+
+// newRenderer builds the default Renderer. When templateDir is set, any
+// *.tmpl file found there is parsed and replaces the built-in template
+// of the same name; files with no built-in counterpart are loaded too,
+// so a custom template can {{template}} into them.
+func newRenderer(templateDir string) (Renderer, error) {
+	tmpl, err := template.New("root").Parse(builtinTemplates)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema2go: invalid built-in template: %v", err)
+	}
+	if templateDir != "" {
+		matches, err := filepath.Glob(filepath.Join(templateDir, "*.tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf("jsonschema2go: invalid --templates dir %q: %v", templateDir, err)
+		}
+		for _, match := range matches {
+			data, err := ioutil.ReadFile(match)
+			if err != nil {
+				return nil, fmt.Errorf("jsonschema2go: could not read template %v: %v", match, err)
+			}
+			if _, err := tmpl.New(filepath.Base(match)).Parse(string(data)); err != nil {
+				return nil, fmt.Errorf("jsonschema2go: could not parse template %v: %v", match, err)
+			}
+		}
+	}
+	return &templateRenderer{tmpl: tmpl}, nil
+}
+
+func (r *templateRenderer) Header(w io.Writer, data HeaderData) error {
+	return r.tmpl.ExecuteTemplate(w, "header.tmpl", data)
+}
+
+func (r *templateRenderer) Struct(w io.Writer, data StructData) error {
+	return r.tmpl.ExecuteTemplate(w, "struct.tmpl", data)
+}
+
+func (r *templateRenderer) Field(w io.Writer, data FieldData) error {
+	return r.tmpl.ExecuteTemplate(w, "field.tmpl", data)
+}
+
+func (r *templateRenderer) Enum(w io.Writer, data EnumData) error {
+	return r.tmpl.ExecuteTemplate(w, "enum.tmpl", data)
+}
@@ -0,0 +1,96 @@
+package jsonschema2go
+
+// Schema is the in-memory representation of a (sub)schema document. Only
+// the keywords jsonschema2go understands are represented explicitly.
+type Schema struct {
+	SchemaURI     string             `json:"$schema,omitempty"`
+	ID            string             `json:"$id,omitempty"`
+	Ref           string             `json:"$ref,omitempty"`
+	RecursiveRef  string             `json:"$recursiveRef,omitempty"`
+	Anchor        string             `json:"$anchor,omitempty"`
+	DynamicAnchor string             `json:"$dynamicAnchor,omitempty"`
+	Title         string             `json:"title,omitempty"`
+	Description   string             `json:"description,omitempty"`
+	Type          interface{}        `json:"type,omitempty"`
+	Properties    map[string]*Schema `json:"properties,omitempty"`
+	Required      []string           `json:"required,omitempty"`
+	Items         *Schema            `json:"items,omitempty"`
+	Definitions   map[string]*Schema `json:"definitions,omitempty"`
+	Defs          map[string]*Schema `json:"$defs,omitempty"`
+	// UnevaluatedProperties mirrors the draft 2019-09+ keyword of the
+	// same name. jsonschema2go only special-cases the `false` form: it
+	// rejects any property the generated struct doesn't already know
+	// about (see unevaluated.go).
+	UnevaluatedProperties *bool         `json:"unevaluatedProperties,omitempty"`
+	Enum                  []interface{} `json:"enum,omitempty"`
+	Const                 interface{}   `json:"const,omitempty"`
+	OneOf                 []*Schema     `json:"oneOf,omitempty"`
+	AnyOf                 []*Schema     `json:"anyOf,omitempty"`
+	AllOf                 []*Schema     `json:"allOf,omitempty"`
+
+	// numeric constraints
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+
+	// string constraints
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+
+	// array constraints
+	MinItems    *int `json:"minItems,omitempty"`
+	MaxItems    *int `json:"maxItems,omitempty"`
+	UniqueItems bool `json:"uniqueItems,omitempty"`
+
+	// object constraints
+	MinProperties *int `json:"minProperties,omitempty"`
+	MaxProperties *int `json:"maxProperties,omitempty"`
+
+	// resolvedURL is the URL this schema document was loaded from, as
+	// given in Job.URLs. It is only recorded: $ref values are matched
+	// literally, not resolved relative to it.
+	resolvedURL string
+}
+
+// schemaDocument is a root schema together with the url it was loaded from
+// and the json schema draft detected for it (see draft.go).
+type schemaDocument struct {
+	url   string
+	root  *Schema
+	draft string
+}
+
+// defsFor returns the definitions map to search for same-document $ref
+// targets such as "#/definitions/Foo" or "#/$defs/Foo", merging the
+// legacy `definitions` keyword with draft 2019-09+'s `$defs` so both
+// resolve regardless of which draft a schema declares.
+func defsFor(schema *Schema) map[string]*Schema {
+	if len(schema.Defs) == 0 {
+		return schema.Definitions
+	}
+	if len(schema.Definitions) == 0 {
+		return schema.Defs
+	}
+	merged := make(map[string]*Schema, len(schema.Definitions)+len(schema.Defs))
+	for k, v := range schema.Definitions {
+		merged[k] = v
+	}
+	for k, v := range schema.Defs {
+		merged[k] = v
+	}
+	return merged
+}
+
+// isObjectSchema reports whether s should be generated as a go struct.
+func isObjectSchema(s *Schema) bool {
+	if s == nil {
+		return false
+	}
+	if t, ok := s.Type.(string); ok {
+		return t == "object"
+	}
+	return len(s.Properties) > 0
+}
@@ -0,0 +1,30 @@
+package jsonschema2go
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// writeUnevaluatedGuard emits an UnmarshalJSON method for t that rejects
+// any json property not already declared on t, implementing
+// "unevaluatedProperties: false" at the round-trip marshalling boundary.
+// It is a no-op when t's schema does not set unevaluatedProperties to
+// false.
+func (g *generator) writeUnevaluatedGuard(buf *bytes.Buffer, t *goType) {
+	if t.schema.UnevaluatedProperties == nil || *t.schema.UnevaluatedProperties {
+		return
+	}
+	fmt.Fprintf(buf, "func (v *%s) UnmarshalJSON(data []byte) error {\n", t.name)
+	fmt.Fprintf(buf, "\ttype alias %s\n", t.name)
+	fmt.Fprintf(buf, "\tvar a alias\n")
+	fmt.Fprintf(buf, "\tif err := json.Unmarshal(data, &a); err != nil {\n\t\treturn err\n\t}\n")
+	fmt.Fprintf(buf, "\tvar raw map[string]json.RawMessage\n")
+	fmt.Fprintf(buf, "\tif err := json.Unmarshal(data, &raw); err != nil {\n\t\treturn err\n\t}\n")
+	fmt.Fprintf(buf, "\tfor k := range raw {\n\t\tswitch k {\n")
+	for _, f := range t.fields {
+		fmt.Fprintf(buf, "\t\tcase %q:\n", f.jsonName)
+	}
+	fmt.Fprintf(buf, "\t\tdefault:\n\t\t\treturn fmt.Errorf(\"unknown property %%q (unevaluatedProperties is false)\", k)\n")
+	fmt.Fprintf(buf, "\t\t}\n\t}\n")
+	fmt.Fprintf(buf, "\t*v = %s(a)\n\treturn nil\n}\n\n", t.name)
+}
@@ -0,0 +1,269 @@
+package jsonschema2go
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationError is returned by a generated Validate() method when a
+// value does not satisfy a constraint declared in its source json
+// schema. Pointer is the JSON pointer (RFC 6901), relative to the root
+// schema, of the schema of the field whose constraint was violated.
+type ValidationError struct {
+	Pointer string
+	Err     error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Pointer, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// constraintCheck is a single boolean expression (true meaning the
+// constraint is violated) paired with the message to report when it
+// fires.
+type constraintCheck struct {
+	cond string
+	msg  string
+}
+
+// writeValidator emits a Validate() error method for t, enforcing the
+// constraint keywords present in its source schema. Object-typed fields
+// are validated by delegating to their own Validate() method.
+func (g *generator) writeValidator(buf *bytes.Buffer, t *goType) {
+	fmt.Fprintf(buf, "func (v *%s) Validate() error {\n", t.name)
+	for _, f := range t.fields {
+		g.writeFieldConstraints(buf, f)
+	}
+	fmt.Fprintf(buf, "\treturn nil\n}\n\n")
+}
+
+// writeFieldConstraints emits the checks for a single field, each
+// returning a *ValidationError pointing at the field's json pointer on
+// failure.
+func (g *generator) writeFieldConstraints(buf *bytes.Buffer, f goField) {
+	s := f.schema
+	accessor := "v." + f.name
+
+	if f.required {
+		if cond := requiredZeroCondition(f.goType, accessor); cond != "" {
+			fmt.Fprintf(buf, "\tif %s {\n", cond)
+			fmt.Fprintf(buf, "\t\treturn &jsonschema2go.ValidationError{Pointer: %q, Err: fmt.Errorf(%q)}\n", f.pointer, "required value is missing")
+			fmt.Fprintf(buf, "\t}\n")
+		}
+	}
+	for _, c := range g.fieldConstraintChecks(accessor, s) {
+		fmt.Fprintf(buf, "\tif %s {\n", c.cond)
+		fmt.Fprintf(buf, "\t\treturn &jsonschema2go.ValidationError{Pointer: %q, Err: fmt.Errorf(%q)}\n", f.pointer, c.msg)
+		fmt.Fprintf(buf, "\t}\n")
+	}
+	for _, sub := range s.AllOf {
+		// a value satisfying allOf must satisfy every subschema's own
+		// keywords applied to that same value, so just inline each
+		// subschema's checks against the field as if they were its own.
+		g.writeFieldConstraints(buf, goField{
+			name: f.name, jsonName: f.jsonName, goType: f.goType,
+			schema: sub, required: f.required, pointer: f.pointer,
+		})
+	}
+	if len(s.OneOf) > 0 {
+		fmt.Fprintf(buf, "\tif %s != 1 {\n", g.subschemaMatchCountExpr(accessor, s.OneOf))
+		fmt.Fprintf(buf, "\t\treturn &jsonschema2go.ValidationError{Pointer: %q, Err: fmt.Errorf(%q)}\n", f.pointer, "value does not match exactly one of the oneOf schemas")
+		fmt.Fprintf(buf, "\t}\n")
+	}
+	if len(s.AnyOf) > 0 {
+		fmt.Fprintf(buf, "\tif %s == 0 {\n", g.subschemaMatchCountExpr(accessor, s.AnyOf))
+		fmt.Fprintf(buf, "\t\treturn &jsonschema2go.ValidationError{Pointer: %q, Err: fmt.Errorf(%q)}\n", f.pointer, "value does not match any of the anyOf schemas")
+		fmt.Fprintf(buf, "\t}\n")
+	}
+	if isObjectSchema(s) {
+		fmt.Fprintf(buf, "\tif err := %s.Validate(); err != nil {\n\t\treturn err\n\t}\n", accessor)
+	}
+}
+
+// fieldConstraintChecks returns the boolean conditions (each true when
+// violated) implied by s's own keywords against accessor, independent of
+// any particular field's required-ness. It is also used to build the
+// match expression for a oneOf/anyOf subschema (see
+// subschemaMatchCountExpr).
+func (g *generator) fieldConstraintChecks(accessor string, s *Schema) []constraintCheck {
+	var checks []constraintCheck
+	add := func(cond, msg string) { checks = append(checks, constraintCheck{cond, msg}) }
+
+	if s.Minimum != nil {
+		add(fmt.Sprintf("float64(%s) < %v", accessor, *s.Minimum), "value below minimum")
+	}
+	if s.ExclusiveMinimum != nil {
+		add(fmt.Sprintf("float64(%s) <= %v", accessor, *s.ExclusiveMinimum), "value at or below exclusiveMinimum")
+	}
+	if s.Maximum != nil {
+		add(fmt.Sprintf("float64(%s) > %v", accessor, *s.Maximum), "value above maximum")
+	}
+	if s.ExclusiveMaximum != nil {
+		add(fmt.Sprintf("float64(%s) >= %v", accessor, *s.ExclusiveMaximum), "value at or above exclusiveMaximum")
+	}
+	if s.MultipleOf != nil {
+		add(fmt.Sprintf("math.Mod(float64(%s), %v) != 0", accessor, *s.MultipleOf), "value is not a multiple of multipleOf")
+	}
+	if s.MinLength != nil {
+		add(fmt.Sprintf("len(%s) < %d", accessor, *s.MinLength), "string shorter than minLength")
+	}
+	if s.MaxLength != nil {
+		add(fmt.Sprintf("len(%s) > %d", accessor, *s.MaxLength), "string longer than maxLength")
+	}
+	if s.Pattern != "" {
+		add(fmt.Sprintf("!%s.MatchString(%s)", g.patternVar(s.Pattern), accessor), "string does not match pattern")
+	}
+	if s.MinItems != nil {
+		add(fmt.Sprintf("len(%s) < %d", accessor, *s.MinItems), "array shorter than minItems")
+	}
+	if s.MaxItems != nil {
+		add(fmt.Sprintf("len(%s) > %d", accessor, *s.MaxItems), "array longer than maxItems")
+	}
+	if s.UniqueItems {
+		add(duplicateItemCondition(accessor), "array items are not unique")
+	}
+	if isObjectSchema(s) {
+		if s.MinProperties != nil {
+			add(fmt.Sprintf("%s < %d", objectPropertyCountExpr(accessor, s), *s.MinProperties), "object has fewer properties than minProperties")
+		}
+		if s.MaxProperties != nil {
+			add(fmt.Sprintf("%s > %d", objectPropertyCountExpr(accessor, s), *s.MaxProperties), "object has more properties than maxProperties")
+		}
+	}
+	if s.Const != nil && !isObjectSchema(s) {
+		add(fmt.Sprintf("%s != %#v", accessor, s.Const), "value does not equal const")
+	}
+	if len(s.Enum) > 0 && !(g.job.StrongEnums && !isObjectSchema(s)) {
+		// When StrongEnums is set, enum is not checked here: a field whose
+		// own schema has an enum gets a named enum type that already
+		// rejects unknown values on unmarshal (UnknownEnumValueError). An
+		// enum inside an allOf/oneOf/anyOf subschema is then not checked.
+		add(enumMembershipCondition(accessor, s.Enum), "value is not one of the enum values")
+	}
+	return checks
+}
+
+// enumMembershipCondition builds the boolean expression that is true when
+// accessor does NOT equal any of the allowed enum values.
+func enumMembershipCondition(accessor string, enum []interface{}) string {
+	cond := ""
+	for i, v := range enum {
+		if i > 0 {
+			cond += " && "
+		}
+		cond += fmt.Sprintf("%s != %#v", accessor, v)
+	}
+	return cond
+}
+
+// duplicateItemCondition builds a boolean expression, true when accessor
+// (a slice) contains two equal elements, for uniqueItems. It compares
+// elements with ==, so it compiles only for a comparable element type,
+// such as string/int64/float64/bool; slices of slices, or of generated
+// structs that contain slices, are not comparable.
+func duplicateItemCondition(accessor string) string {
+	return fmt.Sprintf(
+		"func() bool {\n\t\tfor i := range %s {\n\t\t\tfor j := range %s[i+1:] {\n\t\t\t\tif %s[i] == %s[i+1+j] {\n\t\t\t\t\treturn true\n\t\t\t\t}\n\t\t\t}\n\t\t}\n\t\treturn false\n\t}()",
+		accessor, accessor, accessor, accessor,
+	)
+}
+
+// requiredZeroCondition builds the boolean expression, true when accessor
+// is at its zero value, for goType's "required" check. Numeric/boolean
+// fields are skipped: their zero value (0/false) is itself a legitimate
+// json value, so presence cannot be distinguished from absence without
+// the generated struct separately tracking which keys were seen on the
+// wire, which this generator does not do.
+func requiredZeroCondition(goType, accessor string) string {
+	switch {
+	case goType == "string":
+		return accessor + ` == ""`
+	case strings.HasPrefix(goType, "[]"):
+		return fmt.Sprintf("len(%s) == 0", accessor)
+	default:
+		return ""
+	}
+}
+
+// objectPropertyCountExpr builds an expression counting how many of s's
+// declared properties are set (not at their go zero value) on accessor,
+// approximating minProperties/maxProperties. Properties whose own schema
+// is itself an object are not counted: the generated struct for them has
+// no single zero-value literal it can be compared against (a struct
+// literal comparison requires every field to be comparable, which isn't
+// guaranteed here).
+func objectPropertyCountExpr(accessor string, s *Schema) string {
+	keys := make([]string, 0, len(s.Properties))
+	for k := range s.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		prop := s.Properties[k]
+		if isObjectSchema(prop) {
+			continue
+		}
+		fieldAccessor := accessor + "." + exportedName(k, prop.Title)
+		if t, _ := prop.Type.(string); t == "array" {
+			parts = append(parts, fmt.Sprintf("len(%s) != 0", fieldAccessor))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s != %s", fieldAccessor, zeroLiteralFor(prop)))
+	}
+	if len(parts) == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("func() int {\n\t\tn := 0\n\t\tfor _, set := range []bool{%s} {\n\t\t\tif set {\n\t\t\t\tn++\n\t\t\t}\n\t\t}\n\t\treturn n\n\t}()", strings.Join(parts, ", "))
+}
+
+// zeroLiteralFor returns the go zero-value literal for a scalar-typed
+// schema, matching the type goTypeFor would generate for it.
+func zeroLiteralFor(s *Schema) string {
+	t, _ := s.Type.(string)
+	switch t {
+	case "integer":
+		return "0"
+	case "number":
+		return "0"
+	case "boolean":
+		return "false"
+	default:
+		return `""`
+	}
+}
+
+// subschemaMatchExpr builds a boolean expression, true when accessor
+// satisfies every check fieldConstraintChecks derives from sub, reusing
+// the same checks writeFieldConstraints emits as early returns. sub's
+// own allOf/oneOf/anyOf and required keywords are not included.
+func (g *generator) subschemaMatchExpr(accessor string, sub *Schema) string {
+	checks := g.fieldConstraintChecks(accessor, sub)
+	if len(checks) == 0 {
+		return "true"
+	}
+	conds := make([]string, len(checks))
+	for i, c := range checks {
+		conds[i] = c.cond
+	}
+	return fmt.Sprintf("!(%s)", strings.Join(conds, " || "))
+}
+
+// subschemaMatchCountExpr builds an expression counting how many of subs
+// accessor satisfies, for oneOf ("== 1") and anyOf ("> 0") checks. Since
+// this generator picks a single concrete go type for a field regardless
+// of oneOf/anyOf, this only validates each alternative's constraint
+// keywords against that one type, not polymorphic type dispatch.
+func (g *generator) subschemaMatchCountExpr(accessor string, subs []*Schema) string {
+	terms := make([]string, len(subs))
+	for i, sub := range subs {
+		terms[i] = fmt.Sprintf("func() int {\n\t\tif %s {\n\t\t\treturn 1\n\t\t}\n\t\treturn 0\n\t}()", g.subschemaMatchExpr(accessor, sub))
+	}
+	return "(" + strings.Join(terms, " + ") + ")"
+}